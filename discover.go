@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Discoverer finds the routes of a Next.js project under root. It returns
+// the concrete, statically-warmable routes it found, plus any dynamic
+// route templates (still using bracket syntax, e.g. "/blog/[id]") that
+// need parameter values from the params manifest before they can be
+// warmed, plus the HTTP method to use for any returned route that has a
+// detected one (e.g. POST for a route.ts handler that only exports POST),
+// keyed by route path. A route with no entry in methods should be warmed
+// with GET.
+type Discoverer interface {
+	Discover(root string) (routes []string, dynamic []RouteTemplate, methods map[string]string, err error)
+}
+
+// httpHandlerMethods are the exported function/const names App Router
+// recognizes as HTTP method handlers in a route.ts/route.js file, in the
+// order detectRouteHandlerMethod prefers them.
+var httpHandlerMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// AppRouterDiscoverer finds routes using App Router conventions: a route
+// is defined by a 'page.*' file, and optionally by a 'route.*' file
+// (a Route Handler) when IncludeRouteHandlers is set.
+type AppRouterDiscoverer struct {
+	// IncludeRouteHandlers also warms up route.ts/route.js endpoints,
+	// which App Router treats as warmable handlers rather than pages.
+	IncludeRouteHandlers bool
+
+	// RouteHandlerMethod is the HTTP method to warm a route.ts/route.js
+	// handler with when it doesn't export any of httpHandlerMethods (e.g.
+	// the handler is a default export, or uses a pattern this tool
+	// doesn't recognize). Defaults to GET when empty.
+	RouteHandlerMethod string
+}
+
+// Discover recursively scans the App Router 'app' directory, applying the
+// route-group, parallel-route, intercepting-route, and private-folder
+// conventions implemented by normalizeAppRoute.
+func (d AppRouterDiscoverer) Discover(root string) ([]string, []RouteTemplate, map[string]string, error) {
+	routeSet := make(map[string]struct{})
+	dynamicSet := make(map[string]struct{})
+	methods := make(map[string]string)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		fileName := entry.Name()
+		isPageFile := !entry.IsDir() && isAppRouterFile(fileName, "page")
+		isRouteHandler := d.IncludeRouteHandlers && !entry.IsDir() && isAppRouterFile(fileName, "route")
+		if !isPageFile && !isRouteHandler {
+			return nil
+		}
+
+		route, ok, err := relativeRoute(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		finalRoute, dynamic, ok := normalizeAppRoute(route)
+		if !ok {
+			return nil
+		}
+
+		if dynamic {
+			dynamicSet[finalRoute] = struct{}{}
+		} else {
+			routeSet[finalRoute] = struct{}{}
+			if isRouteHandler {
+				methods[finalRoute] = detectRouteHandlerMethod(path, d.RouteHandlerMethod)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return setToRoutes(routeSet), dynamicTemplates(dynamicSet), methods, nil
+}
+
+// detectRouteHandlerMethod scans a route.ts/route.js file for its exported
+// HTTP method handlers (e.g. "export function POST(" or "export const
+// PUT ="), returning the most appropriate one to warm up with: GET if the
+// handler exports it (the common case), otherwise the earliest other
+// exported method in httpHandlerMethods order, otherwise fallback (or "GET"
+// if fallback is empty).
+func detectRouteHandlerMethod(path, fallback string) string {
+	if fallback == "" {
+		fallback = http.MethodGet
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fallback
+	}
+	content := string(data)
+
+	for _, method := range httpHandlerMethods {
+		if exportsHandlerMethod(content, method) {
+			return method
+		}
+	}
+	return fallback
+}
+
+// exportsHandlerMethod reports whether content exports method as either a
+// named function or a const arrow function, the two conventions Next.js
+// route handlers use.
+func exportsHandlerMethod(content, method string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "export function "+method+"(") ||
+			strings.HasPrefix(line, "export async function "+method+"(") ||
+			strings.HasPrefix(line, "export const "+method+" ") ||
+			strings.HasPrefix(line, "export const "+method+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// isAppRouterFile reports whether fileName is a convention file of the
+// given kind ("page" or "route") with one of the recognized extensions.
+func isAppRouterFile(fileName, kind string) bool {
+	if !strings.HasPrefix(fileName, kind+".") {
+		return false
+	}
+	return strings.HasSuffix(fileName, ".js") || strings.HasSuffix(fileName, ".jsx") ||
+		strings.HasSuffix(fileName, ".ts") || strings.HasSuffix(fileName, ".tsx")
+}
+
+// normalizeAppRoute applies App Router segment conventions (private
+// folders, route groups, intercepting routes, parallel route slots, and
+// dynamic segments) to a raw directory-based route, returning the final
+// route, whether it contains a dynamic segment, and whether it should be
+// warmed at all.
+func normalizeAppRoute(route string) (finalRoute string, dynamic bool, ok bool) {
+	segments := strings.Split(route, "/")
+	var finalSegments []string
+
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "_") {
+			log.Printf("Info: Skipping path with private segment: %s", route)
+			return "", false, false
+		}
+		if strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")") && !strings.HasPrefix(segment, "(...") {
+			continue
+		}
+		if segment == "(.)" || segment == "(..)" || segment == "(...)" {
+			log.Printf("Info: Skipping intercepting route: %s", route)
+			return "", false, false
+		}
+		if strings.HasPrefix(segment, "@") {
+			log.Printf("Info: Skipping parallel route slot: %s", route)
+			return "", false, false
+		}
+		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+			dynamic = true
+		}
+		finalSegments = append(finalSegments, segment)
+	}
+
+	finalRoute = "/" + strings.Join(finalSegments, "/")
+	if finalRoute == "//" {
+		finalRoute = "/"
+	}
+	return finalRoute, dynamic, true
+}
+
+// PagesRouterDiscoverer finds routes using the classic Pages Router
+// conventions: index.* maps to its parent directory's route, nested files
+// map to nested routes, and _app/_document/_error are excluded.
+type PagesRouterDiscoverer struct {
+	// IncludeAPI also warms up files under pages/api, which are request
+	// handlers rather than pages.
+	IncludeAPI bool
+}
+
+// Discover recursively scans the Pages Router 'pages' directory. It never
+// returns a non-nil methods map: pages/api handlers are always warmed with
+// GET, since Next.js dispatches a Pages Router API route to a single
+// handler function regardless of method.
+func (d PagesRouterDiscoverer) Discover(root string) ([]string, []RouteTemplate, map[string]string, error) {
+	routeSet := make(map[string]struct{})
+	dynamicSet := make(map[string]struct{})
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		fileName := entry.Name()
+		if !hasPagesExtension(fileName) {
+			return nil
+		}
+
+		base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		if base == "_app" || base == "_document" || base == "_error" {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if !d.IncludeAPI && (relPath == "api" || strings.HasPrefix(relPath, "api/")) {
+			return nil
+		}
+
+		route, dynamic := normalizePagesRoute(relPath, base)
+		if dynamic {
+			dynamicSet[route] = struct{}{}
+		} else {
+			routeSet[route] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return setToRoutes(routeSet), dynamicTemplates(dynamicSet), nil, nil
+}
+
+// hasPagesExtension reports whether fileName has one of the extensions
+// Next.js resolves Pages Router routes from.
+func hasPagesExtension(fileName string) bool {
+	for _, ext := range []string{".js", ".jsx", ".ts", ".tsx"} {
+		if strings.HasSuffix(fileName, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePagesRoute converts a file path relative to 'pages' (e.g.
+// "blog/[id].tsx" or "settings/index.ts") into its route, reporting
+// whether the route contains a dynamic segment.
+func normalizePagesRoute(relPath, base string) (route string, dynamic bool) {
+	dir := filepath.Dir(relPath)
+	var segments []string
+	if dir != "." {
+		segments = strings.Split(dir, "/")
+	}
+	if base != "index" {
+		segments = append(segments, base)
+	}
+
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+			dynamic = true
+		}
+	}
+
+	if len(segments) == 0 {
+		return "/", dynamic
+	}
+	return "/" + strings.Join(segments, "/"), dynamic
+}
+
+// relativeRoute converts routeDir, an absolute-or-relative directory path,
+// into a "/"-separated route relative to root.
+func relativeRoute(root, routeDir string) (route string, ok bool, err error) {
+	relPath, err := filepath.Rel(root, routeDir)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get relative path for %s: %w", routeDir, err)
+	}
+
+	route = filepath.ToSlash(relPath)
+	if route == "." {
+		return "/", true, nil
+	}
+	return "/" + route, true, nil
+}
+
+// setToRoutes converts a set of unique routes into a slice.
+func setToRoutes(set map[string]struct{}) []string {
+	routes := make([]string, 0, len(set))
+	for r := range set {
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// dynamicTemplates converts a set of unique dynamic route paths into bare
+// RouteTemplate values (with no Params yet), for the caller to match
+// against its params manifest.
+func dynamicTemplates(set map[string]struct{}) []RouteTemplate {
+	templates := make([]RouteTemplate, 0, len(set))
+	for path := range set {
+		templates = append(templates, RouteTemplate{Path: path})
+	}
+	return templates
+}
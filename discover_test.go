@@ -0,0 +1,135 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeAppRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		route       string
+		wantRoute   string
+		wantDynamic bool
+		wantOK      bool
+	}{
+		{"root", "/", "/", false, true},
+		{"static nested", "/blog/post", "/blog/post", false, true},
+		{"dynamic segment", "/blog/[id]", "/blog/[id]", true, true},
+		{"catch-all segment", "/docs/[...slug]", "/docs/[...slug]", true, true},
+		{"route group is stripped", "/(marketing)/about", "/about", false, true},
+		{"private folder is skipped", "/_components/button", "", false, false},
+		{"parallel route slot is skipped", "/@modal/login", "", false, false},
+		{"intercepting route is skipped", "/(...)/photo", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, dynamic, ok := normalizeAppRoute(tt.route)
+			if ok != tt.wantOK {
+				t.Fatalf("normalizeAppRoute(%q) ok = %v, want %v", tt.route, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if route != tt.wantRoute || dynamic != tt.wantDynamic {
+				t.Errorf("normalizeAppRoute(%q) = (%q, %v), want (%q, %v)", tt.route, route, dynamic, tt.wantRoute, tt.wantDynamic)
+			}
+		})
+	}
+}
+
+func TestNormalizePagesRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		relPath     string
+		base        string
+		wantRoute   string
+		wantDynamic bool
+	}{
+		{"top-level index", "index.tsx", "index", "/", false},
+		{"nested index", "settings/index.ts", "index", "/settings", false},
+		{"static file", "about.tsx", "about", "/about", false},
+		{"nested static file", "blog/archive.tsx", "archive", "/blog/archive", false},
+		{"dynamic segment", "blog/[id].tsx", "[id]", "/blog/[id]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, dynamic := normalizePagesRoute(tt.relPath, tt.base)
+			if route != tt.wantRoute || dynamic != tt.wantDynamic {
+				t.Errorf("normalizePagesRoute(%q, %q) = (%q, %v), want (%q, %v)", tt.relPath, tt.base, route, dynamic, tt.wantRoute, tt.wantDynamic)
+			}
+		})
+	}
+}
+
+func TestDetectRouteHandlerMethod(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		fallback string
+		want     string
+	}{
+		{
+			name:     "exports GET",
+			content:  "export function GET(request) {\n  return new Response('ok')\n}\n",
+			fallback: "",
+			want:     http.MethodGet,
+		},
+		{
+			name:     "prefers GET over POST",
+			content:  "export async function POST(request) {}\nexport function GET(request) {}\n",
+			fallback: "",
+			want:     http.MethodGet,
+		},
+		{
+			name:     "exports only POST as const arrow function",
+			content:  "export const POST = async (request) => {}\n",
+			fallback: "",
+			want:     http.MethodPost,
+		},
+		{
+			name:     "exports only PUT, no space before equals",
+			content:  "export const PUT=async (request) => {}\n",
+			fallback: "",
+			want:     http.MethodPut,
+		},
+		{
+			name:     "no recognized export falls back to default GET",
+			content:  "export default function handler(request) {}\n",
+			fallback: "",
+			want:     http.MethodGet,
+		},
+		{
+			name:     "no recognized export falls back to configured method",
+			content:  "export default function handler(request) {}\n",
+			fallback: http.MethodPost,
+			want:     http.MethodPost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "route.ts")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write test route file: %v", err)
+			}
+
+			got := detectRouteHandlerMethod(path, tt.fallback)
+			if got != tt.want {
+				t.Errorf("detectRouteHandlerMethod(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectRouteHandlerMethodMissingFile(t *testing.T) {
+	got := detectRouteHandlerMethod("/nonexistent/route.ts", "")
+	if got != http.MethodGet {
+		t.Errorf("detectRouteHandlerMethod on missing file = %q, want %q", got, http.MethodGet)
+	}
+}
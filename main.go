@@ -1,14 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io/fs"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sort"
-	"strings"
 	"time"
 )
 
@@ -17,20 +15,51 @@ const (
 )
 
 // main is the entry point of the application.
-// It finds Next.js routes, sorts them, and makes HTTP requests to "warm them up".
+// It finds Next.js routes, sorts them, and warms them up concurrently.
 func main() {
 	log.SetFlags(0) // Keep log output clean.
 
-	// 1. Find the Next.js 'app' directory.
-	appPath := findAppDirectory()
-
-	// 2. Discover all static, non-dynamic routes.
+	concurrency := flag.Int("concurrency", 4, "number of routes to warm up in parallel")
+	rps := flag.Float64("rps", 0, "maximum warm-up requests per second (0 = unlimited)")
+	retries := flag.Int("retries", 2, "number of retries for 5xx responses or timeouts")
+	backoff := flag.Duration("backoff", 500*time.Millisecond, "base delay for exponential backoff between retries")
+	paramsManifest := flag.String("config", "next-dev-eager.config.json", "path to a JSON or YAML manifest of dynamic route templates and their params")
+	paramsFrom := flag.String("params-from", "", "URL to fetch params for manifest templates that set neither their own params nor paramsFrom")
+	includeRouteHandlers := flag.Bool("route-handlers", false, "also warm up App Router route.ts/route.js handlers")
+	routeHandlerMethod := flag.String("route-handler-method", "GET", "HTTP method to warm a route.ts/route.js handler with when it doesn't export GET or any other recognized method")
+	includeAPI := flag.Bool("api-routes", false, "also warm up Pages Router pages/api handlers")
+	sitemap := flag.String("sitemap", "", "URL or file path to a sitemap.xml to warm up (falls back to <baseURL>/sitemap.xml and robots.txt if unset)")
+	since := flag.Duration("since", 0, "skip sitemap URLs with a <lastmod> older than this (0 = no filtering)")
+	watchMode := flag.Bool("watch", false, "after the initial warm-up, watch the app directory and re-warm routes on change")
+	statusAddr := flag.String("status-addr", ":7777", "address for the -watch status endpoint")
+	output := flag.String("output", "table", "result output format: table, har, or json")
+	outputFile := flag.String("output-file", "", "file to write -output har/json results to (default warmup.har / warmup.json)")
+	metricsAddr := flag.String("metrics-addr", "", "if set, expose Prometheus metrics on this address (e.g. :9090)")
+	flag.Parse()
+
+	// 1. Discover routes from whichever router conventions the project uses.
 	fmt.Println("Discovering routes...")
-	routes, err := findStaticRoutes(appPath)
+	routes, dynamic, methods, err := discoverRoutes(*includeRouteHandlers, *routeHandlerMethod, *includeAPI)
 	if err != nil {
 		log.Fatalf("Error discovering routes: %v", err)
 	}
 
+	// 1b. Expand any dynamic route templates from the params manifest, if present.
+	manifestTemplates, dynamicRoutes, err := expandDynamicRoutes(*paramsManifest, *paramsFrom)
+	if err != nil {
+		log.Fatalf("Error expanding dynamic routes: %v", err)
+	}
+	routes = append(routes, dynamicRoutes...)
+	warnUnresolvedDynamicRoutes(dynamic, manifestTemplates)
+
+	// 1c. Add any URLs discovered from a sitemap, which can include
+	// production-shape dynamic content the filesystem walker can't see.
+	sitemapRoutes, err := loadSitemapRoutes(*sitemap, baseURL, *since)
+	if err != nil {
+		log.Fatalf("Error loading sitemap: %v", err)
+	}
+	routes = append(routes, sitemapRoutes...)
+
 	if len(routes) == 0 {
 		fmt.Println("No static routes found to warm up.")
 		return
@@ -41,153 +70,140 @@ func main() {
 		return len(routes[i]) < len(routes[j])
 	})
 
-	fmt.Printf("Found %d static routes. Warming them up...\n\n", len(routes))
+	fmt.Printf("Found %d routes. Warming them up with %d worker(s)...\n\n", len(routes), *concurrency)
 
-	// 4. Sequentially warm up each route.
+	// 4. Warm up routes concurrently, subject to the configured rate limit.
 	client := &http.Client{
 		Timeout: 15 * time.Second, // Generous timeout for potentially slow server-side rendering on first load.
 	}
 
-	for _, route := range routes {
-		url := baseURL + route
-		fmt.Printf("GET %s ... ", url)
-
-		start := time.Now()
-		resp, err := client.Get(url)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			// Don't stop for a single failed request; continue to the next.
-			time.Sleep(1 * time.Second)
-			continue
-		}
-
-		// It's good practice to close the body, even if we don't read it.
-		resp.Body.Close()
-
-		fmt.Printf("[%s] in %v\n", resp.Status, time.Since(start).Round(time.Millisecond))
+	opts := warmupOptions{
+		Concurrency: *concurrency,
+		RPS:         *rps,
+		Retries:     *retries,
+		Backoff:     *backoff,
+	}
 
-		// 5. Wait for 1 second before the next request as requested.
-		time.Sleep(1 * time.Second)
+	var metrics *metricsRegistry
+	if *metricsAddr != "" {
+		metrics = newMetricsRegistry()
+		serveMetrics(*metricsAddr, metrics)
 	}
 
-	fmt.Println("\nWarm-up complete.")
-}
+	if *output != "table" && *output != "har" && *output != "json" {
+		log.Fatalf("Error: unknown -output %q (expected table, har, or json)", *output)
+	}
 
-// findAppDirectory searches for the Next.js 'app' directory in common locations.
-// It checks for 'app/' and 'src/app/' and returns the path if found.
-// If neither is found, it terminates the program with a fatal error.
-func findAppDirectory() string {
-	possibleAppDirs := []string{"app", "src/app"}
-	for _, dir := range possibleAppDirs {
-		if _, err := os.Stat(dir); err == nil {
-			log.Printf("Found app directory at: ./%s", dir)
-			return dir
+	// Always warm up via the traced path so -metrics-addr reports
+	// observations regardless of -output; only har/json additionally write
+	// a timing file.
+	traced := warmUpAllTraced(routes, client, opts, metrics, methods)
+	results := tracedToWarmResults(traced)
+
+	if *output == "table" {
+		printSummary(results)
+	} else {
+		if err := writeTracedOutput(*output, *outputFile, traced); err != nil {
+			log.Fatalf("Error writing %s output: %v", *output, err)
 		}
 	}
 
-	log.Fatalf("Error: Could not find 'app' or 'src/app' directory. This tool must be run from the root of a Next.js app router project.")
-	return "" // Unreachable, but satisfies compiler.
-}
-
-// findStaticRoutes recursively scans the 'app' directory to find all static Next.js routes.
-// It identifies routes by looking for 'page.tsx', 'page.js', etc., files.
-// It correctly interprets Next.js App Router conventions, filtering out dynamic routes,
-// route groups, parallel routes, and private folders, as these cannot be "woken up"
-// without specific parameters or are not part of the standard URL structure.
-func findStaticRoutes(root string) ([]string, error) {
-	routeSet := make(map[string]struct{})
+	fmt.Println("\nWarm-up complete.")
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			// Propagate errors from walking the directory.
-			return err
+	// 6. Optionally stick around, re-warming routes as files change.
+	if *watchMode {
+		tracker := newStatusTracker()
+		for _, r := range results {
+			tracker.record(r)
 		}
 
-		// A route is defined by the presence of a 'page.*' file.
-		fileName := d.Name()
-		isPageFile := !d.IsDir() && strings.HasPrefix(fileName, "page.") &&
-			(strings.HasSuffix(fileName, ".js") || strings.HasSuffix(fileName, ".jsx") ||
-				strings.HasSuffix(fileName, ".ts") || strings.HasSuffix(fileName, ".tsx"))
-
-		if !isPageFile {
-			return nil
+		var targets []watchTarget
+		if appPath, ok := findFirstDir("app", "src/app"); ok {
+			targets = append(targets, watchTarget{Dir: appPath, IncludeRouteHandlers: *includeRouteHandlers})
+		}
+		if pagesPath, ok := findFirstDir("pages", "src/pages"); ok {
+			targets = append(targets, watchTarget{Dir: pagesPath, Pages: true})
+		}
+		if len(targets) == 0 {
+			log.Fatalf("Error: -watch requires an 'app', 'src/app', 'pages', or 'src/pages' directory.")
 		}
 
-		// The directory containing the page file defines the route's path.
-		routePath := filepath.Dir(path)
+		serveStatus(*statusAddr, tracker)
+		if err := watch(targets, client, opts, tracker, methods); err != nil {
+			log.Fatalf("Error watching: %v", err)
+		}
+	}
+}
 
-		// Get path relative to the 'app' directory root.
-		relPath, err := filepath.Rel(root, routePath)
+// discoverRoutes runs every Discoverer whose directory convention is
+// present in the current project (App Router's 'app'/'src/app', Pages
+// Router's 'pages'/'src/pages') so that a project using either, or both,
+// styles can be warmed up with one invocation. methods maps each returned
+// route.ts/route.js handler route to the HTTP method detected for it (e.g.
+// POST for a handler that doesn't export GET).
+func discoverRoutes(includeRouteHandlers bool, routeHandlerMethod string, includeAPI bool) (routes []string, dynamic []RouteTemplate, methods map[string]string, err error) {
+	found := false
+	methods = make(map[string]string)
+
+	if appPath, ok := findFirstDir("app", "src/app"); ok {
+		found = true
+		log.Printf("Found App Router directory at: ./%s", appPath)
+		r, d, m, err := (AppRouterDiscoverer{IncludeRouteHandlers: includeRouteHandlers, RouteHandlerMethod: routeHandlerMethod}).Discover(appPath)
 		if err != nil {
-			// This is unexpected if the path is from WalkDir starting at root.
-			return fmt.Errorf("failed to get relative path for %s: %w", path, err)
+			return nil, nil, nil, err
 		}
-
-		// Normalize to forward slashes for URLs, regardless of OS.
-		route := filepath.ToSlash(relPath)
-
-		// The root of the walk is '.', which corresponds to the root route '/'.
-		if route == "." {
-			route = "/"
-		} else {
-			route = "/" + route
+		routes = append(routes, r...)
+		dynamic = append(dynamic, d...)
+		for route, method := range m {
+			methods[route] = method
 		}
+	}
 
-		// Process path segments to handle Next.js conventions.
-		segments := strings.Split(route, "/")
-		var finalSegments []string
-		for _, segment := range segments {
-			if segment == "" {
-				continue
-			}
-			// Private folders (e.g., `_components`) are not part of the route path.
-			// Any path containing such a segment is not a public route.
-			if strings.HasPrefix(segment, "_") {
-				log.Printf("Info: Skipping path with private segment: %s", route)
-				return nil // Skip this entire path.
-			}
-			// Route groups (e.g., `(marketing)`) are for organization and don't affect the URL.
-			if strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")") && !strings.HasPrefix(segment, "(...") {
-				continue
-			}
-			// Intercepting routes are a special case and do not define a canonical URL to be warmed up.
-			if segment == "(.)" || segment == "(..)" || segment == "(...)" {
-				log.Printf("Info: Skipping intercepting route: %s", route)
-				return nil
-			}
-			// Parallel routes (e.g., `@team`) are rendered in the same URL and are not separate routes.
-			if strings.HasPrefix(segment, "@") {
-				log.Printf("Info: Skipping parallel route slot: %s", route)
-				return nil
-			}
-			// Dynamic routes (e.g., `[id]` or `[...slug]`) cannot be warmed up without specific params.
-			if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
-				log.Printf("Info: Skipping dynamic route: %s", route)
-				return nil
-			}
-			finalSegments = append(finalSegments, segment)
+	if pagesPath, ok := findFirstDir("pages", "src/pages"); ok {
+		found = true
+		log.Printf("Found Pages Router directory at: ./%s", pagesPath)
+		r, d, m, err := (PagesRouterDiscoverer{IncludeAPI: includeAPI}).Discover(pagesPath)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-
-		// Reconstruct the clean, final route.
-		finalRoute := "/" + strings.Join(finalSegments, "/")
-		// Handle cases where all segments were stripped (e.g., root page in a group).
-		if finalRoute == "//" {
-			finalRoute = "/"
+		routes = append(routes, r...)
+		dynamic = append(dynamic, d...)
+		for route, method := range m {
+			methods[route] = method
 		}
+	}
 
-		routeSet[finalRoute] = struct{}{}
-		return nil
-	})
+	if !found {
+		log.Fatalf("Error: Could not find an 'app', 'src/app', 'pages', or 'src/pages' directory. This tool must be run from the root of a Next.js project.")
+	}
 
-	if err != nil {
-		return nil, err
+	return routes, dynamic, methods, nil
+}
+
+// findFirstDir returns the first of candidates that exists as a directory
+// relative to the current working directory.
+func findFirstDir(candidates ...string) (string, bool) {
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, true
+		}
 	}
+	return "", false
+}
 
-	// Convert the set of unique routes to a slice for sorting and iteration.
-	routes := make([]string, 0, len(routeSet))
-	for r := range routeSet {
-		routes = append(routes, r)
+// warnUnresolvedDynamicRoutes logs a hint for every dynamic route the
+// discoverers found that has no corresponding entry in the params
+// manifest, so users know which routes to add to it.
+func warnUnresolvedDynamicRoutes(discovered, manifestTemplates []RouteTemplate) {
+	inManifest := make(map[string]struct{}, len(manifestTemplates))
+	for _, t := range manifestTemplates {
+		inManifest[t.Path] = struct{}{}
 	}
 
-	return routes, nil
+	for _, d := range discovered {
+		if _, ok := inManifest[d.Path]; ok {
+			continue
+		}
+		log.Printf("Info: Skipping dynamic route %s: add it to the params manifest to warm it up", d.Path)
+	}
 }
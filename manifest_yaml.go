@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// parseYAMLManifest parses a params manifest written in YAML instead of
+// JSON. This tool has no dependency on a real YAML library, so it
+// implements just the subset needed for a manifest: a top-level block
+// sequence of mappings, nested block sequences/mappings, scalar strings,
+// and single-line flow sequences of scalars (e.g. "slug: [a, b, c]") for
+// catch-all params — no flow mappings, anchors, or multiline scalars. The
+// parsed tree is re-marshaled to JSON and decoded into []RouteTemplate so
+// it goes through exactly the same validation as the JSON path.
+//
+// This repo has no go.mod, so pulling in gopkg.in/yaml.v3 for this isn't a
+// one-line decision: it's the same trade-off as the hand-rolled mtime-based
+// watcher (see watchPollInterval) and the HAR/Prometheus formats in
+// output.go/metrics.go. If this tool ever gets proper module management,
+// all three should move to real dependencies together rather than one at a
+// time.
+func parseYAMLManifest(data []byte) ([]RouteTemplate, error) {
+	node, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	asJSON, err := json.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("converting YAML to JSON: %w", err)
+	}
+
+	var templates []RouteTemplate
+	if err := json.Unmarshal(asJSON, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// yamlLine is one non-blank, non-comment line of a YAML document, with
+// its leading-space indentation already measured.
+type yamlLine struct {
+	indent int
+	text   string
+}
+
+// parseYAML parses a minimal block-style YAML document into generic
+// map[string]interface{}/[]interface{}/string values, suitable for
+// round-tripping through encoding/json.
+func parseYAML(data []byte) (interface{}, error) {
+	lines := yamlLines(string(data))
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	node, _, err := parseYAMLNode(lines, 0, lines[0].indent)
+	return node, err
+}
+
+// yamlLines splits raw YAML text into non-blank, non-comment lines with
+// their indentation measured.
+func yamlLines(data string) []yamlLine {
+	var lines []yamlLine
+	for _, raw := range strings.Split(data, "\n") {
+		noTrailingCR := strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimLeft(noTrailingCR, " ")
+		indent := len(noTrailingCR) - len(trimmed)
+		trimmed = strings.TrimSpace(trimmed)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lines = append(lines, yamlLine{indent: indent, text: trimmed})
+	}
+	return lines
+}
+
+// parseYAMLNode parses either a sequence or a mapping starting at pos,
+// all of whose lines share the given indent, and returns the value plus
+// the index of the first line not consumed.
+func parseYAMLNode(lines []yamlLine, pos, indent int) (interface{}, int, error) {
+	if pos < len(lines) && (lines[pos].text == "-" || strings.HasPrefix(lines[pos].text, "- ")) {
+		return parseYAMLSequence(lines, pos, indent)
+	}
+	return parseYAMLMapping(lines, pos, indent)
+}
+
+// parseYAMLSequence parses a "- item" list at the given indent.
+func parseYAMLSequence(lines []yamlLine, pos, indent int) (interface{}, int, error) {
+	var items []interface{}
+
+	for pos < len(lines) && lines[pos].indent == indent && strings.HasPrefix(lines[pos].text, "-") {
+		rest := strings.TrimSpace(strings.TrimPrefix(lines[pos].text, "-"))
+
+		switch {
+		case rest == "":
+			// The item's content is a nested block on the following lines.
+			pos++
+			if pos < len(lines) && lines[pos].indent > indent {
+				val, newPos, err := parseYAMLNode(lines, pos, lines[pos].indent)
+				if err != nil {
+					return nil, 0, err
+				}
+				items = append(items, val)
+				pos = newPos
+			} else {
+				items = append(items, nil)
+			}
+
+		case strings.Contains(rest, ":"):
+			// "- key: value" starts an inline mapping; further keys of the
+			// same mapping continue on following lines, indented to align
+			// with where "key" started (indent + 2, for a "- " dash). Any
+			// key whose value is a nested block (e.g. "params:" followed by
+			// a further-indented sequence) is parsed recursively, the same
+			// as a top-level mapping key would be.
+			contIndent := indent + 2
+			m := map[string]interface{}{}
+
+			key, val := splitYAMLKV(rest)
+			pos++
+			v, newPos, err := yamlMappingValue(lines, pos, val, contIndent)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key], pos = v, newPos
+
+			for pos < len(lines) && lines[pos].indent == contIndent {
+				key, val := splitYAMLKV(lines[pos].text)
+				pos++
+				v, newPos, err := yamlMappingValue(lines, pos, val, contIndent)
+				if err != nil {
+					return nil, 0, err
+				}
+				m[key], pos = v, newPos
+			}
+			items = append(items, m)
+
+		default:
+			items = append(items, yamlScalar(rest))
+			pos++
+		}
+	}
+
+	return items, pos, nil
+}
+
+// parseYAMLMapping parses a "key: value" block at the given indent.
+func parseYAMLMapping(lines []yamlLine, pos, indent int) (interface{}, int, error) {
+	m := map[string]interface{}{}
+
+	for pos < len(lines) && lines[pos].indent == indent {
+		key, val := splitYAMLKV(lines[pos].text)
+		pos++
+
+		v, newPos, err := yamlMappingValue(lines, pos, val, indent)
+		if err != nil {
+			return nil, 0, err
+		}
+		m[key], pos = v, newPos
+	}
+
+	return m, pos, nil
+}
+
+// yamlMappingValue resolves the value for a "key: value" line already split
+// into val by splitYAMLKV. A "[...]" val is parsed as a flow sequence of
+// scalars; any other non-empty val is used as a scalar string as-is; an
+// empty val means the key's value is a nested block on the following,
+// further-indented lines (relative to parentIndent), which is parsed
+// recursively.
+func yamlMappingValue(lines []yamlLine, pos int, val string, parentIndent int) (interface{}, int, error) {
+	if strings.HasPrefix(val, "[") && strings.HasSuffix(val, "]") {
+		seq, err := parseYAMLFlowSequence(val)
+		return seq, pos, err
+	}
+	if val != "" {
+		return val, pos, nil
+	}
+	if pos < len(lines) && lines[pos].indent > parentIndent {
+		return parseYAMLNode(lines, pos, lines[pos].indent)
+	}
+	return nil, pos, nil
+}
+
+// parseYAMLFlowSequence parses a single-line "[a, b, c]" flow sequence of
+// scalars, the one flow-collection shape a params manifest actually needs
+// (catch-all segments). Nested flow collections aren't supported.
+func parseYAMLFlowSequence(s string) ([]interface{}, error) {
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	if inner == "" {
+		return []interface{}{}, nil
+	}
+
+	parts := strings.Split(inner, ",")
+	items := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		trimmed := strings.TrimSpace(part)
+		if strings.ContainsAny(trimmed, "[]{}") {
+			return nil, fmt.Errorf("nested flow collections are not supported: %q", s)
+		}
+		items = append(items, yamlScalar(trimmed))
+	}
+	return items, nil
+}
+
+// splitYAMLKV splits a "key: value" line on its first colon. value is ""
+// when the line is just "key:", meaning its value is a nested block.
+func splitYAMLKV(text string) (key, value string) {
+	idx := strings.Index(text, ":")
+	if idx == -1 {
+		return text, ""
+	}
+	key = strings.TrimSpace(text[:idx])
+	value = strings.TrimSpace(text[idx+1:])
+	return key, yamlScalar(value)
+}
+
+// yamlScalar strips the surrounding quotes from a scalar value, if any.
+func yamlScalar(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
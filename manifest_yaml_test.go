@@ -0,0 +1,112 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAMLManifest(t *testing.T) {
+	yaml := `
+- path: /blog/[id]
+  params:
+    - id: first-post
+    - id: second-post
+- path: /docs/[...slug]
+  params:
+    - slug: [a, b, c]
+- path: /about
+  paramsFrom: "https://example.com/params"
+`
+
+	got, err := parseYAMLManifest([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parseYAMLManifest returned unexpected error: %v", err)
+	}
+
+	want := []RouteTemplate{
+		{
+			Path: "/blog/[id]",
+			Params: []map[string]ParamValue{
+				{"id": "first-post"},
+				{"id": "second-post"},
+			},
+		},
+		{
+			Path: "/docs/[...slug]",
+			Params: []map[string]ParamValue{
+				{"slug": "a/b/c"},
+			},
+		},
+		{
+			Path:       "/about",
+			ParamsFrom: "https://example.com/params",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAMLManifest() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseYAMLManifestEmpty(t *testing.T) {
+	got, err := parseYAMLManifest([]byte(""))
+	if err != nil {
+		t.Fatalf("parseYAMLManifest(\"\") returned unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Errorf("parseYAMLManifest(\"\") = %#v, want nil", got)
+	}
+}
+
+func TestParseYAMLFlowSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []interface{}
+		wantErr bool
+	}{
+		{"empty", "[]", []interface{}{}, false},
+		{"single item", "[a]", []interface{}{"a"}, false},
+		{"multiple items", "[a, b, c]", []interface{}{"a", "b", "c"}, false},
+		{"nested flow collection rejected", "[a, [b, c]]", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAMLFlowSequence(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseYAMLFlowSequence(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseYAMLFlowSequence(%q) returned unexpected error: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAMLFlowSequence(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitYAMLKV(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantKey   string
+		wantValue string
+	}{
+		{"path: /about", "path", "/about"},
+		{`paramsFrom: "https://example.com"`, "paramsFrom", "https://example.com"},
+		{"params:", "params", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			key, value := splitYAMLKV(tt.in)
+			if key != tt.wantKey || value != tt.wantValue {
+				t.Errorf("splitYAMLKV(%q) = (%q, %q), want (%q, %q)", tt.in, key, value, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
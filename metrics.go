@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultBuckets mirrors the Prometheus client libraries' default
+// histogram buckets (in seconds).
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// histogram is a minimal Prometheus-compatible histogram: cumulative
+// per-bucket counts plus a running sum and count. This tool has no
+// dependency on the Prometheus client library, so the text exposition
+// format is rendered by hand in metricsRegistry.render.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64 // counts[i] = observations <= buckets[i]
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(seconds float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// metricsRegistry tracks the counters and histograms exposed on
+// -metrics-addr, in Prometheus text exposition format.
+type metricsRegistry struct {
+	mu            sync.Mutex
+	requestsTotal map[string]uint64 // keyed by status label
+	ttfb          *histogram
+	total         *histogram
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		requestsTotal: make(map[string]uint64),
+		ttfb:          newHistogram(defaultBuckets),
+		total:         newHistogram(defaultBuckets),
+	}
+}
+
+// observe records one traced warm-up request's outcome.
+func (m *metricsRegistry) observe(r tracedResult) {
+	status := "error"
+	if r.Err == nil {
+		status = strconv.Itoa(r.StatusCode)
+	}
+
+	m.mu.Lock()
+	m.requestsTotal[status]++
+	m.mu.Unlock()
+
+	if r.Err == nil {
+		m.ttfb.observe((r.Timing.Blocked + r.Timing.DNS + r.Timing.Connect + r.Timing.TLS + r.Timing.Send + r.Timing.Wait).Seconds())
+		m.total.observe(r.Timing.Total.Seconds())
+	}
+}
+
+// render writes the full registry in Prometheus text exposition format.
+func (m *metricsRegistry) render() string {
+	var b strings.Builder
+
+	m.mu.Lock()
+	statuses := make([]string, 0, len(m.requestsTotal))
+	for status := range m.requestsTotal {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	b.WriteString("# HELP nextdev_eager_requests_total Total number of warm-up requests by status\n")
+	b.WriteString("# TYPE nextdev_eager_requests_total counter\n")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "nextdev_eager_requests_total{status=%q} %d\n", status, m.requestsTotal[status])
+	}
+	m.mu.Unlock()
+
+	writeHistogram(&b, "nextdev_eager_ttfb_seconds", "Time to first byte for warm-up requests", m.ttfb)
+	writeHistogram(&b, "nextdev_eager_total_seconds", "Total duration of warm-up requests", m.total)
+
+	return b.String()
+}
+
+func writeHistogram(b *strings.Builder, name, help string, h *histogram) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for i, bound := range h.buckets {
+		fmt.Fprintf(b, "%s_bucket{le=%q} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(b, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count %d\n", name, h.count)
+}
+
+// serveMetrics starts the Prometheus metrics endpoint used by CI
+// dashboards to track cold-compile regressions over time.
+func serveMetrics(addr string, registry *metricsRegistry) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, registry.render())
+	})
+
+	log.Printf("Metrics endpoint listening on %s/metrics", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("Warning: metrics endpoint stopped: %v", err)
+		}
+	}()
+}
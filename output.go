@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// warmUpAllTraced warms every route with the same worker pool and rate
+// limiting as warmUpAll, but captures a full timing breakdown per request
+// for the HAR/JSON output modes and, if metrics is non-nil, records it
+// there too. Results are returned in the same order as routes. methods is
+// the same per-route HTTP method override passed to warmUpAll.
+func warmUpAllTraced(routes []string, client *http.Client, opts warmupOptions, metrics *metricsRegistry, methods map[string]string) []tracedResult {
+	limiter := newRateLimiter(opts.RPS)
+	defer limiter.Close()
+
+	results := make([]tracedResult, len(routes))
+
+	type job struct {
+		index int
+		route string
+	}
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				limiter.Wait()
+				result := warmRouteTraced(client, j.route, opts, methodFor(j.route, methods))
+				results[j.index] = result
+				if metrics != nil {
+					metrics.observe(result)
+				}
+			}
+		}()
+	}
+
+	for i, route := range routes {
+		jobs <- job{index: i, route: route}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
+
+// harDocument, harLog, and friends model just enough of the HTTP Archive
+// 1.2 format (https://w3c.github.io/web-performance/specs/HAR/Overview.html)
+// for warm-up results to open cleanly in Chrome DevTools or WebPageTest.
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string     `json:"method"`
+	URL         string     `json:"url"`
+	HTTPVersion string     `json:"httpVersion"`
+	Cookies     []struct{} `json:"cookies"`
+	Headers     []struct{} `json:"headers"`
+	QueryString []struct{} `json:"queryString"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Cookies     []struct{} `json:"cookies"`
+	Headers     []struct{} `json:"headers"`
+	Content     harContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// msec converts a duration to HAR's fractional milliseconds, using -1 for
+// phases that didn't happen (e.g. no TLS handshake, connection reused).
+func msec(d time.Duration) float64 {
+	if d <= 0 {
+		return -1
+	}
+	return float64(d.Microseconds()) / 1000.0
+}
+
+// WriteHAR writes results as a HAR 1.2 document to path.
+func WriteHAR(path string, results []tracedResult) error {
+	doc := harDocument{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "next-dev-eager", Version: "1.0"},
+		},
+	}
+
+	now := time.Now()
+	for _, r := range results {
+		status := r.StatusCode
+		statusText := r.Status
+		if r.Err != nil {
+			status = 0
+			statusText = r.Err.Error()
+		}
+
+		doc.Log.Entries = append(doc.Log.Entries, harEntry{
+			StartedDateTime: now.Format(time.RFC3339),
+			Time:            msec(r.Timing.Total),
+			Request: harRequest{
+				Method:      r.Method,
+				URL:         targetURL(r.Route),
+				HTTPVersion: "HTTP/1.1",
+			},
+			Response: harResponse{
+				Status:      status,
+				StatusText:  statusText,
+				HTTPVersion: "HTTP/1.1",
+				Content:     harContent{MimeType: "text/html"},
+			},
+			Timings: harTimings{
+				Blocked: msec(r.Timing.Blocked),
+				DNS:     msec(r.Timing.DNS),
+				Connect: msec(r.Timing.Connect),
+				SSL:     msec(r.Timing.TLS),
+				Send:    msec(r.Timing.Send),
+				Wait:    msec(r.Timing.Wait),
+				Receive: msec(r.Timing.Receive),
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding HAR: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// jsonResult is the per-route shape written in -output json mode.
+type jsonResult struct {
+	Route      string  `json:"route"`
+	Status     string  `json:"status,omitempty"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	BlockedMs  float64 `json:"blockedMs"`
+	DNSMs      float64 `json:"dnsMs"`
+	ConnectMs  float64 `json:"connectMs"`
+	TLSMs      float64 `json:"tlsMs"`
+	SendMs     float64 `json:"sendMs"`
+	WaitMs     float64 `json:"waitMs"`
+	ReceiveMs  float64 `json:"receiveMs"`
+	TotalMs    float64 `json:"totalMs"`
+}
+
+// writeTracedOutput writes traced results to outputFile in the given
+// format ("har" or "json"), defaulting the file name to warmup.<format>
+// when outputFile is empty.
+func writeTracedOutput(format, outputFile string, results []tracedResult) error {
+	if outputFile == "" {
+		outputFile = "warmup." + format
+	}
+
+	switch format {
+	case "har":
+		if err := WriteHAR(outputFile, results); err != nil {
+			return err
+		}
+	case "json":
+		if err := WriteJSON(outputFile, results); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Wrote %s (%s format)\n", outputFile, format)
+	return nil
+}
+
+// tracedToWarmResults adapts tracedResults to warmResults so that the
+// table summary and the -watch status tracker (which only deal in
+// warmResults) can use the initial traced warm-up, whatever -output is.
+func tracedToWarmResults(results []tracedResult) []warmResult {
+	out := make([]warmResult, len(results))
+	for i, r := range results {
+		out[i] = warmResult{
+			Route:    r.Route,
+			Status:   r.Status,
+			Duration: r.Timing.Total,
+			Retries:  r.Retries,
+			Err:      r.Err,
+		}
+	}
+	return out
+}
+
+// WriteJSON writes one JSON object per route, as a JSON array, to path.
+func WriteJSON(path string, results []tracedResult) error {
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		jr := jsonResult{
+			Route:      r.Route,
+			Status:     r.Status,
+			StatusCode: r.StatusCode,
+			BlockedMs:  msec(r.Timing.Blocked),
+			DNSMs:      msec(r.Timing.DNS),
+			ConnectMs:  msec(r.Timing.Connect),
+			TLSMs:      msec(r.Timing.TLS),
+			SendMs:     msec(r.Timing.Send),
+			WaitMs:     msec(r.Timing.Wait),
+			ReceiveMs:  msec(r.Timing.Receive),
+			TotalMs:    msec(r.Timing.Total),
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		out = append(out, jr)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON output: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
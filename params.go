@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RouteTemplate describes a dynamic Next.js route and the concrete
+// parameter values it should be expanded with. Path uses the route's
+// on-disk segment syntax (e.g. "/blog/[id]", "/docs/[...slug]",
+// "/shop/[[...slug]]"), and each entry in Params supplies one set of values
+// to substitute, producing one concrete URL per entry.
+//
+// Catch-all params ([...slug]) are given as a JSON array of segments (e.g.
+// {"slug": ["a", "b", "c"]}), matching generateStaticParams; a single
+// pre-joined string (e.g. {"slug": "a/b/c"}) is also accepted as a
+// shorthand. An optional catch-all ([[...slug]]) is omitted from the
+// expanded URL entirely when its value is missing or empty, producing the
+// parameterless route.
+//
+// ParamsFrom, if set, is a URL to fetch this template's Params from; the
+// fetched values replace any Params given in the manifest. It mirrors the
+// Next.js
+// generateStaticParams contract: a GET request returning a JSON array of
+// parameter objects.
+type RouteTemplate struct {
+	Path       string                  `json:"path"`
+	Params     []map[string]ParamValue `json:"params,omitempty"`
+	ParamsFrom string                  `json:"paramsFrom,omitempty"`
+}
+
+// ParamValue is a single route param's value. It unmarshals from either a
+// JSON string (used as-is) or a JSON array of strings (joined with "/"),
+// so catch-all segments can be written either way.
+type ParamValue string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *ParamValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*p = ParamValue(s)
+		return nil
+	}
+
+	var segments []string
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return fmt.Errorf("param value must be a string or array of strings: %w", err)
+	}
+	*p = ParamValue(strings.Join(segments, "/"))
+	return nil
+}
+
+// segmentKind classifies a single path segment of a route template.
+type segmentKind int
+
+const (
+	segStatic segmentKind = iota
+	segDynamic
+	segCatchAll
+	segOptionalCatchAll
+)
+
+// expandDynamicRoutes loads the params manifest at manifestPath, if it
+// exists, resolves any templates that fetch their params from a running
+// app, applies the global paramsFrom fallback, and expands the result into
+// concrete URLs. A missing manifest file is not an error: it simply means
+// the project has no dynamic routes to warm. The manifest templates (before
+// expansion) are also returned so the caller can tell which discovered
+// dynamic routes still have no params.
+func expandDynamicRoutes(manifestPath, paramsFrom string) (manifestTemplates []RouteTemplate, routes []string, err error) {
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+
+	templates, err := LoadParamsManifest(manifestPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if paramsFrom != "" {
+		for i, t := range templates {
+			if t.ParamsFrom == "" && len(t.Params) == 0 {
+				templates[i].ParamsFrom = paramsFrom
+			}
+		}
+	}
+
+	resolved, err := ResolveParams(templates)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return templates, ExpandRoutes(resolved), nil
+}
+
+// LoadParamsManifest reads a JSON or YAML manifest (e.g.
+// next-dev-eager.config.json/.yaml) describing dynamic route templates and
+// their parameter values. The manifest is a top-level array of
+// RouteTemplate objects; the format is chosen from path's extension
+// (.yaml/.yml vs everything else, which is parsed as JSON).
+func LoadParamsManifest(path string) ([]RouteTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading params manifest %q: %w", path, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		templates, err := parseYAMLManifest(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing params manifest %q: %w", path, err)
+		}
+		return templates, nil
+	}
+
+	var templates []RouteTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("parsing params manifest %q: %w", path, err)
+	}
+
+	return templates, nil
+}
+
+// FetchParamsFrom fetches a JSON array of parameter objects from a running
+// app, mirroring the Next.js generateStaticParams contract. This lets
+// users reuse the same source of truth their build uses instead of
+// duplicating it in a manifest.
+func FetchParamsFrom(url string) ([]map[string]ParamValue, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching params from %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching params from %q: unexpected status %s", url, resp.Status)
+	}
+
+	var params []map[string]ParamValue
+	if err := json.NewDecoder(resp.Body).Decode(&params); err != nil {
+		return nil, fmt.Errorf("decoding params from %q: %w", url, err)
+	}
+
+	return params, nil
+}
+
+// ResolveParams fills in each template's Params by fetching from
+// ParamsFrom where set. Templates that already have Params, and have no
+// ParamsFrom, are left untouched.
+func ResolveParams(templates []RouteTemplate) ([]RouteTemplate, error) {
+	resolved := make([]RouteTemplate, len(templates))
+	for i, t := range templates {
+		if t.ParamsFrom == "" {
+			resolved[i] = t
+			continue
+		}
+
+		params, err := FetchParamsFrom(t.ParamsFrom)
+		if err != nil {
+			return nil, err
+		}
+		t.Params = params
+		resolved[i] = t
+	}
+	return resolved, nil
+}
+
+// ExpandRoutes expands a set of route templates into concrete, warmable
+// URLs by substituting each template's dynamic segments with each of its
+// parameter value sets. Templates with no Params are returned unexpanded.
+func ExpandRoutes(templates []RouteTemplate) []string {
+	var routes []string
+
+	for _, t := range templates {
+		if len(t.Params) == 0 {
+			routes = append(routes, t.Path)
+			continue
+		}
+
+		for _, params := range t.Params {
+			route, err := expandTemplate(t.Path, params)
+			if err != nil {
+				fmt.Printf("Warning: skipping %s: %v\n", t.Path, err)
+				continue
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// expandTemplate substitutes the dynamic segments of a single route
+// template path using one set of parameter values.
+func expandTemplate(path string, params map[string]ParamValue) (string, error) {
+	segments := strings.Split(path, "/")
+	result := make([]string, 0, len(segments))
+
+	for _, seg := range segments {
+		if seg == "" {
+			continue
+		}
+
+		name, kind := parseDynamicSegment(seg)
+		if kind == segStatic {
+			result = append(result, seg)
+			continue
+		}
+
+		value, ok := params[name]
+
+		if kind == segOptionalCatchAll {
+			if !ok || value == "" {
+				continue
+			}
+			result = append(result, string(value))
+			continue
+		}
+
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing value for param %q", name)
+		}
+		result = append(result, string(value))
+	}
+
+	return "/" + strings.Join(result, "/"), nil
+}
+
+// parseDynamicSegment classifies a single path segment and, for dynamic
+// segments, returns the bare parameter name with its brackets and leading
+// "..." stripped.
+func parseDynamicSegment(segment string) (name string, kind segmentKind) {
+	switch {
+	case strings.HasPrefix(segment, "[[...") && strings.HasSuffix(segment, "]]"):
+		return strings.TrimSuffix(strings.TrimPrefix(segment, "[[..."), "]]"), segOptionalCatchAll
+	case strings.HasPrefix(segment, "[...") && strings.HasSuffix(segment, "]"):
+		return strings.TrimSuffix(strings.TrimPrefix(segment, "[..."), "]"), segCatchAll
+	case strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]"):
+		return strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]"), segDynamic
+	default:
+		return segment, segStatic
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		params  map[string]ParamValue
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "static path",
+			path:   "/blog",
+			params: nil,
+			want:   "/blog",
+		},
+		{
+			name:   "single dynamic segment",
+			path:   "/blog/[id]",
+			params: map[string]ParamValue{"id": "hello-world"},
+			want:   "/blog/hello-world",
+		},
+		{
+			name:    "missing dynamic segment",
+			path:    "/blog/[id]",
+			params:  map[string]ParamValue{},
+			wantErr: true,
+		},
+		{
+			name:   "catch-all pre-joined string",
+			path:   "/docs/[...slug]",
+			params: map[string]ParamValue{"slug": "a/b/c"},
+			want:   "/docs/a/b/c",
+		},
+		{
+			name:    "missing catch-all",
+			path:    "/docs/[...slug]",
+			params:  map[string]ParamValue{},
+			wantErr: true,
+		},
+		{
+			name:   "optional catch-all present",
+			path:   "/shop/[[...slug]]",
+			params: map[string]ParamValue{"slug": "shoes/red"},
+			want:   "/shop/shoes/red",
+		},
+		{
+			name:   "optional catch-all absent",
+			path:   "/shop/[[...slug]]",
+			params: map[string]ParamValue{},
+			want:   "/shop",
+		},
+		{
+			name:   "optional catch-all empty value",
+			path:   "/shop/[[...slug]]",
+			params: map[string]ParamValue{"slug": ""},
+			want:   "/shop",
+		},
+		{
+			name: "multiple dynamic segments",
+			path: "/shop/[category]/[id]",
+			params: map[string]ParamValue{
+				"category": "shoes",
+				"id":       "42",
+			},
+			want: "/shop/shoes/42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandTemplate(tt.path, tt.params)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expandTemplate(%q) = %q, want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandTemplate(%q) returned unexpected error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandTemplate(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDynamicSegment(t *testing.T) {
+	tests := []struct {
+		segment  string
+		wantName string
+		wantKind segmentKind
+	}{
+		{"blog", "blog", segStatic},
+		{"[id]", "id", segDynamic},
+		{"[...slug]", "slug", segCatchAll},
+		{"[[...slug]]", "slug", segOptionalCatchAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.segment, func(t *testing.T) {
+			name, kind := parseDynamicSegment(tt.segment)
+			if name != tt.wantName || kind != tt.wantKind {
+				t.Errorf("parseDynamicSegment(%q) = (%q, %v), want (%q, %v)", tt.segment, name, kind, tt.wantName, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestExpandRoutes(t *testing.T) {
+	templates := []RouteTemplate{
+		{Path: "/about"},
+		{
+			Path: "/blog/[id]",
+			Params: []map[string]ParamValue{
+				{"id": "first-post"},
+				{"id": "second-post"},
+			},
+		},
+		{
+			Path: "/shop/[[...slug]]",
+			Params: []map[string]ParamValue{
+				{},
+				{"slug": "shoes/red"},
+			},
+		},
+		{
+			// A missing required param for one entry is skipped with a
+			// warning rather than aborting the whole expansion.
+			Path: "/docs/[...slug]",
+			Params: []map[string]ParamValue{
+				{"slug": "intro"},
+				{},
+			},
+		},
+	}
+
+	got := ExpandRoutes(templates)
+	want := []string{
+		"/about",
+		"/blog/first-post",
+		"/blog/second-post",
+		"/shop",
+		"/shop/shoes/red",
+		"/docs/intro",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandRoutes() = %v, want %v", got, want)
+	}
+}
+
+func TestParamValueUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ParamValue
+		wantErr bool
+	}{
+		{"string", `"hello"`, "hello", false},
+		{"array of strings", `["a", "b", "c"]`, "a/b/c", false},
+		{"invalid type", `42`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p ParamValue
+			err := p.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("UnmarshalJSON(%s) = %q, want error", tt.data, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%s) returned unexpected error: %v", tt.data, err)
+			}
+			if p != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %q, want %q", tt.data, p, tt.want)
+			}
+		})
+	}
+}
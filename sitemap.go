@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// sitemapURLSet and sitemapIndex model the two documents defined by the
+// sitemaps.org protocol: a regular sitemap listing pages, and a sitemap
+// index listing other sitemaps.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// LoadSitemap loads and parses a sitemap from a URL or a local file path,
+// following sitemap index files to their sub-sitemaps, and returns every
+// <loc> URL found. URLs whose <lastmod> is older than
+// since are skipped; a zero since disables this filtering.
+func LoadSitemap(source string, since time.Time) ([]string, error) {
+	data, err := readSitemapSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var index sitemapIndex
+	if err := xml.Unmarshal(data, &index); err == nil && len(index.Sitemaps) > 0 {
+		var urls []string
+		for _, entry := range index.Sitemaps {
+			sub, err := LoadSitemap(entry.Loc, since)
+			if err != nil {
+				return nil, fmt.Errorf("loading sub-sitemap %q: %w", entry.Loc, err)
+			}
+			urls = append(urls, sub...)
+		}
+		return urls, nil
+	}
+
+	var set sitemapURLSet
+	if err := xml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing sitemap %q: %w", source, err)
+	}
+
+	var urls []string
+	for _, u := range set.URLs {
+		if u.Loc == "" {
+			continue
+		}
+		if !since.IsZero() && isOlderThan(u.LastMod, since) {
+			continue
+		}
+		urls = append(urls, u.Loc)
+	}
+
+	return urls, nil
+}
+
+// isOlderThan reports whether lastMod (an ISO 8601 / W3C datetime, as used
+// by <lastmod>) parses successfully and is before cutoff. Unparseable or
+// empty values are treated as not older, so they're never skipped.
+func isOlderThan(lastMod string, cutoff time.Time) bool {
+	if lastMod == "" {
+		return false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, lastMod); err == nil {
+			return t.Before(cutoff)
+		}
+	}
+	return false
+}
+
+// readSitemapSource reads raw sitemap bytes from an http(s) URL or a local
+// file path.
+func readSitemapSource(source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 15 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("fetching sitemap %q: %w", source, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching sitemap %q: unexpected status %s", source, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+
+	return os.ReadFile(source)
+}
+
+// loadSitemapRoutes resolves the -sitemap flag's value into a list of
+// URLs to warm. If source is empty, it falls back to discovering a
+// sitemap from baseURL, treating failure to find one as informational
+// rather than fatal, since most dev servers don't serve one.
+func loadSitemapRoutes(source, baseURL string, since time.Duration) ([]string, error) {
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-since)
+	}
+
+	if source != "" {
+		urls, err := LoadSitemap(source, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("loading sitemap %q: %w", source, err)
+		}
+		return urls, nil
+	}
+
+	urls, err := DiscoverSitemapURLs(baseURL, cutoff)
+	if err != nil {
+		fmt.Printf("Info: no sitemap found at %s: %v\n", baseURL, err)
+		return nil, nil
+	}
+	return urls, nil
+}
+
+// DiscoverSitemapURLs finds warmable URLs without an explicit -sitemap
+// flag, by fetching "<baseURL>/sitemap.xml" and falling back to
+// "<baseURL>/robots.txt" for "Sitemap:" directives when that fails.
+func DiscoverSitemapURLs(baseURL string, since time.Time) ([]string, error) {
+	if urls, err := LoadSitemap(baseURL+"/sitemap.xml", since); err == nil {
+		return urls, nil
+	}
+
+	sitemaps, err := discoverSitemapsFromRobots(baseURL + "/robots.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, sitemap := range sitemaps {
+		found, err := LoadSitemap(sitemap, since)
+		if err != nil {
+			return nil, fmt.Errorf("loading sitemap %q from robots.txt: %w", sitemap, err)
+		}
+		urls = append(urls, found...)
+	}
+
+	return urls, nil
+}
+
+// discoverSitemapsFromRobots fetches robots.txt and returns the sitemap
+// URLs listed in its "Sitemap:" directives.
+func discoverSitemapsFromRobots(robotsURL string) ([]string, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(robotsURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching robots.txt %q: %w", robotsURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching robots.txt %q: unexpected status %s", robotsURL, resp.Status)
+	}
+
+	var sitemaps []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := cutPrefixFold(line, "Sitemap:"); ok {
+			sitemaps = append(sitemaps, strings.TrimSpace(rest))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading robots.txt %q: %w", robotsURL, err)
+	}
+
+	return sitemaps, nil
+}
+
+// cutPrefixFold is a case-insensitive variant of strings.CutPrefix, since
+// the "Sitemap:" directive's casing isn't guaranteed by the spec.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// timing breaks a single HTTP request down the way browser devtools and
+// HAR viewers expect: blocked (waiting for a connection), dns, connect,
+// tls, send, wait (time to first byte), and receive (reading the body).
+type timing struct {
+	Blocked time.Duration
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	Send    time.Duration
+	Wait    time.Duration
+	Receive time.Duration
+	Total   time.Duration
+}
+
+// tracedResult is the outcome of warming a single route with full timing
+// instrumentation, used by the HAR/JSON output modes and by metrics.
+type tracedResult struct {
+	Route      string
+	Method     string
+	StatusCode int
+	Status     string
+	Timing     timing
+	Retries    int
+	Err        error
+}
+
+// warmRouteTraced issues a request for route using method, retrying 5xx
+// responses and transport errors with the same exponential backoff as
+// warmRoute, and captures a browser-devtools-style timing breakdown on the
+// final attempt.
+func warmRouteTraced(client *http.Client, route string, opts warmupOptions, method string) tracedResult {
+	var result tracedResult
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoffDelay(opts.Backoff, attempt))
+		}
+
+		result = warmRouteTracedOnce(client, route, method)
+		result.Retries = attempt
+		if result.Err == nil && result.StatusCode < 500 {
+			break
+		}
+	}
+	return result
+}
+
+// warmRouteTracedOnce issues a single request for route using method, using
+// an httptrace.ClientTrace to capture a browser-devtools-style timing
+// breakdown.
+func warmRouteTracedOnce(client *http.Client, route, method string) tracedResult {
+	url := targetURL(route)
+
+	var tGetConnStart, tGotConn, tDNSStart, tConnectStart, tTLSStart, tWroteRequest, tGotFirstByte time.Time
+	var dns, connect, tlsHandshake time.Duration
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return tracedResult{Route: route, Method: method, Err: err}
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn:              func(string) { tGetConnStart = time.Now() },
+		GotConn:              func(httptrace.GotConnInfo) { tGotConn = time.Now() },
+		DNSStart:             func(httptrace.DNSStartInfo) { tDNSStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { dns = time.Since(tDNSStart) },
+		ConnectStart:         func(string, string) { tConnectStart = time.Now() },
+		ConnectDone:          func(string, string, error) { connect = time.Since(tConnectStart) },
+		TLSHandshakeStart:    func() { tTLSStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { tlsHandshake = time.Since(tTLSStart) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { tWroteRequest = time.Now() },
+		GotFirstResponseByte: func() { tGotFirstByte = time.Now() },
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return tracedResult{Route: route, Method: method, Err: err}
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	total := time.Since(start)
+
+	// GetConn->GotConn already spans DNS/Connect/TLS when a new connection
+	// is dialed, so subtract them out to avoid double-counting that time
+	// when Blocked+DNS+Connect+TLS+Send+Wait are summed (e.g. for TTFB).
+	blocked := tGotConn.Sub(tGetConnStart) - dns - connect - tlsHandshake
+	if blocked < 0 {
+		blocked = 0
+	}
+
+	return tracedResult{
+		Route:      route,
+		Method:     method,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Timing: timing{
+			Blocked: blocked,
+			DNS:     dns,
+			Connect: connect,
+			TLS:     tlsHandshake,
+			Send:    tWroteRequest.Sub(tGotConn),
+			Wait:    tGotFirstByte.Sub(tWroteRequest),
+			Receive: time.Since(tGotFirstByte),
+			Total:   total,
+		},
+	}
+}
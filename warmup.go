@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// warmupOptions controls how the worker pool warms up routes.
+type warmupOptions struct {
+	Concurrency int
+	RPS         float64
+	Retries     int
+	Backoff     time.Duration
+}
+
+// warmResult captures the outcome of warming a single route, including how
+// many attempts it took, for the end-of-run summary table.
+type warmResult struct {
+	Route    string
+	Status   string
+	Duration time.Duration
+	Retries  int
+	Err      error
+}
+
+// rateLimiter is a minimal token-bucket limiter used to cap the number of
+// warm-up requests issued per second. A nil *rateLimiter is treated as
+// unlimited, so callers don't need to special-case the "-rps 0" default.
+type rateLimiter struct {
+	tokens chan struct{}
+	stop   chan struct{}
+}
+
+// newRateLimiter starts a limiter that releases one token every 1/rps
+// seconds. It returns nil when rps is zero or negative, meaning "no limit".
+func newRateLimiter(rps float64) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+
+	interval := time.Duration(float64(time.Second) / rps)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case rl.tokens <- struct{}{}:
+				default:
+					// A token is already waiting to be spent; drop this tick.
+				}
+			case <-rl.stop:
+				return
+			}
+		}
+	}()
+
+	return rl
+}
+
+// Wait blocks until a token is available, or returns immediately for a nil
+// (unlimited) limiter.
+func (rl *rateLimiter) Wait() {
+	if rl == nil {
+		return
+	}
+	<-rl.tokens
+}
+
+// Close stops the limiter's background ticker goroutine.
+func (rl *rateLimiter) Close() {
+	if rl == nil {
+		return
+	}
+	close(rl.stop)
+}
+
+// warmUpAll warms every route using a pool of opts.Concurrency workers,
+// retrying 5xx responses and network/timeout errors with exponential
+// backoff up to opts.Retries times. Results are returned in the same order
+// as routes, regardless of completion order. methods supplies the HTTP
+// method for any route that needs one other than GET (see Discoverer); a
+// nil methods map, or a route with no entry, warms with GET.
+func warmUpAll(routes []string, client *http.Client, opts warmupOptions, methods map[string]string) []warmResult {
+	limiter := newRateLimiter(opts.RPS)
+	defer limiter.Close()
+
+	results := make([]warmResult, len(routes))
+
+	type job struct {
+		index int
+		route string
+	}
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				limiter.Wait()
+				results[j.index] = warmRoute(client, j.route, opts, methodFor(j.route, methods))
+			}
+		}()
+	}
+
+	for i, route := range routes {
+		jobs <- job{index: i, route: route}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
+
+// warmRoute issues the request for a single route using method, retrying
+// on 5xx responses and transport errors (including timeouts) with
+// exponential backoff plus jitter, up to opts.Retries additional attempts.
+func warmRoute(client *http.Client, route string, opts warmupOptions, method string) warmResult {
+	url := targetURL(route)
+	var lastErr error
+	var lastStatus string
+
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoffDelay(opts.Backoff, attempt))
+		}
+
+		req, err := http.NewRequest(method, url, nil)
+		if err != nil {
+			return warmResult{Route: route, Err: err}
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		duration := time.Since(start)
+
+		if err != nil {
+			lastErr = err
+			if attempt == opts.Retries {
+				return warmResult{Route: route, Duration: duration, Retries: attempt, Err: err}
+			}
+			continue
+		}
+
+		resp.Body.Close()
+		lastStatus = resp.Status
+
+		if resp.StatusCode >= 500 && attempt < opts.Retries {
+			continue
+		}
+
+		return warmResult{Route: route, Status: lastStatus, Duration: duration, Retries: attempt}
+	}
+
+	// Unreachable in practice: the loop above always returns on its final
+	// iteration, but keep a fallback for safety.
+	return warmResult{Route: route, Status: lastStatus, Err: lastErr}
+}
+
+// methodFor looks up the HTTP method to warm route with, defaulting to GET
+// when methods is nil or has no entry for route.
+func methodFor(route string, methods map[string]string) string {
+	if method, ok := methods[route]; ok && method != "" {
+		return method
+	}
+	return http.MethodGet
+}
+
+// targetURL returns route as-is if it's already an absolute URL (e.g. one
+// discovered from a sitemap), or resolves it against baseURL otherwise.
+func targetURL(route string) string {
+	if strings.HasPrefix(route, "http://") || strings.HasPrefix(route, "https://") {
+		return route
+	}
+	return baseURL + route
+}
+
+// backoffDelay computes an exponential backoff duration for the given
+// attempt number (1-indexed), with up to 20% random jitter to avoid
+// thundering-herd retries against the dev server.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << uint(attempt-1)
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay + jitter
+}
+
+// printSummary prints a simple aligned table of route, status, duration,
+// and retry count once all warm-up attempts have completed.
+func printSummary(results []warmResult) {
+	fmt.Println()
+	fmt.Println("Summary:")
+	fmt.Printf("%-40s %-12s %-12s %s\n", "ROUTE", "STATUS", "DURATION", "RETRIES")
+	for _, r := range results {
+		status := r.Status
+		if r.Err != nil {
+			status = fmt.Sprintf("ERROR: %v", r.Err)
+		}
+		fmt.Printf("%-40s %-12s %-12s %d\n", r.Route, status, r.Duration.Round(time.Millisecond), r.Retries)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	for attempt := 1; attempt <= 4; attempt++ {
+		delay := backoffDelay(base, attempt)
+		min := base << uint(attempt-1)
+		// Up to 20% jitter is added on top of the base exponential delay.
+		max := min + min/5 + 1
+		if delay < min || delay > max {
+			t.Errorf("backoffDelay(%v, %d) = %v, want in [%v, %v]", base, attempt, delay, min, max)
+		}
+	}
+}
+
+func TestMethodFor(t *testing.T) {
+	methods := map[string]string{"/api/hello": http.MethodPost}
+
+	tests := []struct {
+		name   string
+		route  string
+		methds map[string]string
+		want   string
+	}{
+		{"known route uses its method", "/api/hello", methods, http.MethodPost},
+		{"unknown route defaults to GET", "/about", methods, http.MethodGet},
+		{"nil methods defaults to GET", "/api/hello", nil, http.MethodGet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := methodFor(tt.route, tt.methds)
+			if got != tt.want {
+				t.Errorf("methodFor(%q, %v) = %q, want %q", tt.route, tt.methds, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarmRouteRetriesOn5xxThenSucceeds(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	opts := warmupOptions{Retries: 2, Backoff: time.Millisecond}
+	result := warmRoute(server.Client(), server.URL, opts, http.MethodGet)
+
+	if result.Err != nil {
+		t.Fatalf("warmRoute() returned unexpected error: %v", result.Err)
+	}
+	if result.Retries != 2 {
+		t.Errorf("warmRoute() Retries = %d, want 2", result.Retries)
+	}
+	if requests != 3 {
+		t.Errorf("server received %d requests, want 3", requests)
+	}
+}
+
+func TestWarmRouteGivesUpAfterRetriesExhausted(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	opts := warmupOptions{Retries: 2, Backoff: time.Millisecond}
+	result := warmRoute(server.Client(), server.URL, opts, http.MethodGet)
+
+	if result.Err != nil {
+		t.Fatalf("warmRoute() returned unexpected error: %v", result.Err)
+	}
+	if result.Retries != 2 {
+		t.Errorf("warmRoute() Retries = %d, want 2", result.Retries)
+	}
+	if requests != 3 {
+		t.Errorf("server received %d requests, want 3 (1 initial + 2 retries)", requests)
+	}
+}
+
+func TestWarmRouteDoesNotRetryOn4xx(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	opts := warmupOptions{Retries: 2, Backoff: time.Millisecond}
+	result := warmRoute(server.Client(), server.URL, opts, http.MethodGet)
+
+	if result.Err != nil {
+		t.Fatalf("warmRoute() returned unexpected error: %v", result.Err)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1 (no retry on 4xx)", requests)
+	}
+}
+
+func TestWarmRouteUsesGivenMethod(t *testing.T) {
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	opts := warmupOptions{Retries: 0, Backoff: time.Millisecond}
+	result := warmRoute(server.Client(), server.URL, opts, http.MethodPost)
+
+	if result.Err != nil {
+		t.Fatalf("warmRoute() returned unexpected error: %v", result.Err)
+	}
+	if gotMethod != http.MethodPost {
+		t.Errorf("server received method %q, want %q", gotMethod, http.MethodPost)
+	}
+}
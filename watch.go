@@ -0,0 +1,466 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchPollInterval is how often the filesystem is polled for changes.
+// There's no fsnotify dependency here (this tool has none, and this repo
+// has no go.mod to add one to), so changes are detected by diffing file
+// modification times instead. Each poll only stats the convention files a
+// router actually cares about (see targetWatchable) rather than every file
+// under app/ or pages/, so a large project's components, styles, tests, and
+// other incidental files don't get restated on every tick.
+const watchPollInterval = 250 * time.Millisecond
+
+// watchDebounce is how long to wait for changes to settle (e.g. an editor
+// writing several files on save) before re-warming.
+const watchDebounce = 500 * time.Millisecond
+
+// routeStatus is the last known state of a warmed route, served over the
+// status endpoint for editor integrations.
+type routeStatus struct {
+	Route      string    `json:"route"`
+	LastWarmed time.Time `json:"lastWarmed"`
+	Duration   string    `json:"duration"`
+	Status     string    `json:"status"`
+}
+
+// statusTracker records the most recent warm-up result for each route,
+// safe for concurrent use by the watch loop and the status HTTP handler.
+type statusTracker struct {
+	mu      sync.Mutex
+	byRoute map[string]routeStatus
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{byRoute: make(map[string]routeStatus)}
+}
+
+func (s *statusTracker) record(result warmResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := result.Status
+	if result.Err != nil {
+		status = fmt.Sprintf("error: %v", result.Err)
+	}
+	s.byRoute[result.Route] = routeStatus{
+		Route:      result.Route,
+		LastWarmed: time.Now(),
+		Duration:   result.Duration.Round(time.Millisecond).String(),
+		Status:     status,
+	}
+}
+
+func (s *statusTracker) snapshot() []routeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]routeStatus, 0, len(s.byRoute))
+	for _, st := range s.byRoute {
+		out = append(out, st)
+	}
+	return out
+}
+
+// serveStatus starts the tiny status endpoint used by editor integrations
+// to see which routes are hot.
+func serveStatus(addr string, tracker *statusTracker) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tracker.snapshot())
+	})
+
+	log.Printf("Status endpoint listening on %s/status", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("Warning: status endpoint stopped: %v", err)
+		}
+	}()
+}
+
+// routeDirIndex maps a route's source directory (relative to an App
+// Router root, "/"-separated) to the route it serves, so that a change to
+// an ancestor layout.*/loading.* file (or to the route.*/page.* file
+// itself) can be resolved back to a route. includeRouteHandlers also
+// indexes route.ts/route.js directories, mirroring AppRouterDiscoverer, so
+// -watch can re-warm a route handler after an edit to it.
+func routeDirIndex(appPath string, includeRouteHandlers bool) (map[string]string, error) {
+	index := make(map[string]string)
+
+	err := filepath.WalkDir(appPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		isPageFile := !entry.IsDir() && isAppRouterFile(entry.Name(), "page")
+		isRouteHandler := includeRouteHandlers && !entry.IsDir() && isAppRouterFile(entry.Name(), "route")
+		if !isPageFile && !isRouteHandler {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		relDir, ok, err := relativeRoute(appPath, dir)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		route, dynamic, ok := normalizeAppRoute(relDir)
+		if !ok || dynamic {
+			return nil
+		}
+
+		relDirSlash, err := filepath.Rel(appPath, dir)
+		if err != nil {
+			return err
+		}
+		relDirSlash = filepath.ToSlash(relDirSlash)
+		if relDirSlash == "." {
+			// Matches affectedRoutes' own "." -> "" normalization for the
+			// root directory, so the root route's key isn't missed.
+			relDirSlash = ""
+		}
+		index[relDirSlash] = route
+		return nil
+	})
+
+	return index, err
+}
+
+// affectedRoutes resolves a changed file under appPath to the routes that
+// need re-warming: just its own route for a page.*/route.* change, or
+// every route nested under its directory for a layout.*/loading.* change,
+// since Next.js recompiles the whole layout chain on first hit.
+func affectedRoutes(appPath, changedFile string, index map[string]string) []string {
+	fileName := filepath.Base(changedFile)
+	dir := filepath.Dir(changedFile)
+	relDir, err := filepath.Rel(appPath, dir)
+	if err != nil {
+		return nil
+	}
+	relDir = filepath.ToSlash(relDir)
+	if relDir == "." {
+		relDir = ""
+	}
+
+	isLayoutChange := isAppRouterFile(fileName, "layout") || isAppRouterFile(fileName, "loading")
+	if !isLayoutChange {
+		if route, ok := index[relDir]; ok {
+			return []string{route}
+		}
+		return nil
+	}
+
+	var routes []string
+	for d, route := range index {
+		if relDir == "" || d == relDir || strings.HasPrefix(d, relDir+"/") {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// watchableFile reports whether a filesystem event on fileName should
+// trigger a re-warm: page, layout, route, or loading convention files.
+func watchableFile(fileName string) bool {
+	for _, kind := range []string{"page", "layout", "route", "loading"} {
+		if isAppRouterFile(fileName, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchTarget is a router root to watch, paired with which file-naming
+// convention governs it, since App Router and Pages Router resolve a
+// changed file to its affected routes differently.
+type watchTarget struct {
+	Dir   string
+	Pages bool
+
+	// IncludeRouteHandlers mirrors the -route-handlers flag: an App
+	// Router target only indexes and re-warms route.ts/route.js
+	// directories when this is set. Unused for a Pages Router target.
+	IncludeRouteHandlers bool
+}
+
+// watch polls each target's directory for changes to its router's
+// convention files and re-warms the affected routes (debounced), until the
+// process is stopped. A project can be watched via App Router, Pages
+// Router, or both at once, mirroring discoverRoutes. methods is the same
+// per-route HTTP method override discoverRoutes produced for the initial
+// warm-up, so re-warming a changed route.ts handler uses the right method.
+func watch(targets []watchTarget, client *http.Client, opts warmupOptions, tracker *statusTracker, methods map[string]string) error {
+	appIndex := make(map[string]map[string]string)
+	pagesIndex := make(map[string]map[string]string)
+	pagesRoutes := make(map[string][]string)
+	mtimes := make(map[string]time.Time)
+
+	for _, t := range targets {
+		if t.Pages {
+			byFile, routes, err := pagesRouteIndex(t.Dir)
+			if err != nil {
+				return fmt.Errorf("indexing routes under %s: %w", t.Dir, err)
+			}
+			pagesIndex[t.Dir] = byFile
+			pagesRoutes[t.Dir] = routes
+		} else {
+			index, err := routeDirIndex(t.Dir, t.IncludeRouteHandlers)
+			if err != nil {
+				return fmt.Errorf("indexing routes under %s: %w", t.Dir, err)
+			}
+			appIndex[t.Dir] = index
+		}
+
+		dirMTimes, err := snapshotMTimes(t.Dir, targetWatchable(t))
+		if err != nil {
+			return fmt.Errorf("watching %s: %w", t.Dir, err)
+		}
+		for path, mtime := range dirMTimes {
+			mtimes[path] = mtime
+		}
+
+		log.Printf("Watching %s for changes (poll every %v, debounce %v)...", t.Dir, watchPollInterval, watchDebounce)
+	}
+
+	pending := make(map[string]struct{})
+	var debounceTimer *time.Timer
+	debounceFire := make(chan struct{})
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			current := make(map[string]time.Time)
+			pollFailed := false
+			for _, t := range targets {
+				dirMTimes, err := snapshotMTimes(t.Dir, targetWatchable(t))
+				if err != nil {
+					log.Printf("Warning: failed to poll %s: %v", t.Dir, err)
+					pollFailed = true
+					continue
+				}
+				for path, mtime := range dirMTimes {
+					current[path] = mtime
+				}
+			}
+			if pollFailed {
+				continue
+			}
+
+			// current only holds the convention files targetWatchable
+			// selected for each target, so any new-or-changed path here is
+			// already known to matter; no further filtering needed.
+			changed := false
+			for path, mtime := range current {
+				if prev, ok := mtimes[path]; !ok || !prev.Equal(mtime) {
+					pending[path] = struct{}{}
+					changed = true
+				}
+			}
+			mtimes = current
+
+			// Only restart the debounce window on a tick that saw a new
+			// change, so it can elapse once changes stop arriving.
+			if changed {
+				if debounceTimer != nil {
+					debounceTimer.Stop()
+				}
+				debounceTimer = time.AfterFunc(watchDebounce, func() {
+					debounceFire <- struct{}{}
+				})
+			}
+
+		case <-debounceFire:
+			routeSet := make(map[string]struct{})
+			for path := range pending {
+				t := targetForPath(targets, path)
+				if t == nil {
+					continue
+				}
+				var routes []string
+				if t.Pages {
+					routes = affectedPagesRoutes(t.Dir, path, pagesIndex[t.Dir], pagesRoutes[t.Dir])
+				} else {
+					routes = affectedRoutes(t.Dir, path, appIndex[t.Dir])
+				}
+				for _, route := range routes {
+					routeSet[route] = struct{}{}
+				}
+			}
+			pending = make(map[string]struct{})
+
+			routes := make([]string, 0, len(routeSet))
+			for route := range routeSet {
+				routes = append(routes, route)
+			}
+			if len(routes) == 0 {
+				continue
+			}
+
+			log.Printf("Change detected, re-warming %d route(s)...", len(routes))
+			results := warmUpAll(routes, client, opts, methods)
+			for _, r := range results {
+				tracker.record(r)
+			}
+			printSummary(results)
+		}
+	}
+}
+
+// targetForPath returns the watchTarget that changedFile falls under, or
+// nil if it doesn't belong to any of them.
+func targetForPath(targets []watchTarget, changedFile string) *watchTarget {
+	for i, t := range targets {
+		if rel, err := filepath.Rel(t.Dir, changedFile); err == nil && !strings.HasPrefix(rel, "..") {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// targetWatchable returns a predicate reporting whether a poll should care
+// about path's modification time under t: the App Router or Pages Router
+// convention files relevant to t, not every file in the tree. Used to keep
+// snapshotMTimes from stat-ing a large app's components, styles, tests, and
+// other incidental files on every poll.
+func targetWatchable(t watchTarget) func(path string) bool {
+	if t.Pages {
+		return func(path string) bool {
+			relPath, err := filepath.Rel(t.Dir, path)
+			if err != nil {
+				return false
+			}
+			return pagesWatchableFile(filepath.ToSlash(relPath))
+		}
+	}
+	return func(path string) bool {
+		return watchableFile(filepath.Base(path))
+	}
+}
+
+// pagesRouteIndex maps every static (non-dynamic) Pages Router page file
+// under pagesPath, outside api/ (relative to pagesPath, "/"-separated), to
+// the route it serves, plus the
+// full list of routes found, used to re-warm every route when a shared
+// _app/_document file changes.
+func pagesRouteIndex(pagesPath string) (byFile map[string]string, routes []string, err error) {
+	byFile = make(map[string]string)
+
+	err = filepath.WalkDir(pagesPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		fileName := entry.Name()
+		if !hasPagesExtension(fileName) {
+			return nil
+		}
+
+		base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		if base == "_app" || base == "_document" || base == "_error" {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(pagesPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath == "api" || strings.HasPrefix(relPath, "api/") {
+			return nil
+		}
+
+		route, dynamic := normalizePagesRoute(relPath, base)
+		if dynamic {
+			return nil
+		}
+		byFile[relPath] = route
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, route := range byFile {
+		routes = append(routes, route)
+	}
+	return byFile, routes, nil
+}
+
+// affectedPagesRoutes resolves a changed file under pagesPath to the
+// routes that need re-warming: every known route for an _app/_document
+// change (Next.js recompiles every page against them), or just the one
+// route a page file maps to.
+func affectedPagesRoutes(pagesPath, changedFile string, byFile map[string]string, allRoutes []string) []string {
+	relPath, err := filepath.Rel(pagesPath, changedFile)
+	if err != nil {
+		return nil
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	base := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	if base == "_app" || base == "_document" {
+		return allRoutes
+	}
+
+	if route, ok := byFile[relPath]; ok {
+		return []string{route}
+	}
+	return nil
+}
+
+// pagesWatchableFile reports whether a filesystem event on relPath (relative
+// to the pages directory, "/"-separated) should trigger a re-warm under
+// Pages Router conventions: any page file outside api/, plus the shared
+// _app/_document files.
+func pagesWatchableFile(relPath string) bool {
+	fileName := filepath.Base(relPath)
+	if !hasPagesExtension(fileName) {
+		return false
+	}
+	if relPath == "api" || strings.HasPrefix(relPath, "api/") {
+		return false
+	}
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	return base != "_error"
+}
+
+// snapshotMTimes walks root and returns the modification time of every file
+// in it for which watchable returns true, keyed by path. Filtering during
+// the walk, rather than after, avoids an Info() stat syscall for every
+// incidental file (components, styles, tests, assets) that could never
+// affect a route.
+func snapshotMTimes(root string, watchable func(path string) bool) (map[string]time.Time, error) {
+	mtimes := make(map[string]time.Time)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !watchable(path) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		mtimes[path] = info.ModTime()
+		return nil
+	})
+	return mtimes, err
+}